@@ -0,0 +1,224 @@
+package log
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultAsyncBufferSize is used by AddOutputAsync when AsyncOptions.BufferSize is not set.
+const defaultAsyncBufferSize = 1024
+
+// OverflowPolicy decides what happens when an async output's buffer is full.
+type OverflowPolicy int
+
+const (
+	// Block makes the logging goroutine wait for room in the buffer. Since
+	// each output is written under its own lock (not the Logger's shared
+	// one), a stalled Block output only ever delays the goroutines that
+	// are themselves logging to it - never a goroutine targeting a
+	// different output. It can still mean an indefinite wait for callers
+	// of this output if its buffer never drains, so a sink that can go
+	// down for a while (e.g. a network collector) should prefer
+	// DropOldest or DropNewest instead.
+	Block OverflowPolicy = iota
+	// DropOldest discards the oldest buffered record to make room for the new one.
+	DropOldest
+	// DropNewest discards the record that would have been logged.
+	DropNewest
+)
+
+// AsyncOptions configures an output added via AddOutputAsync.
+type AsyncOptions struct {
+	// BufferSize is the number of formatted records buffered between the
+	// logging goroutines and the output's writer goroutine. Defaults to
+	// defaultAsyncBufferSize when <= 0.
+	BufferSize int
+	// OverflowPolicy decides what happens once the buffer is full. Defaults
+	// to Block.
+	OverflowPolicy OverflowPolicy
+}
+
+// AsyncOutput is the handle returned by AddOutputAsync. It lets callers
+// monitor how many records this output has dropped because its buffer
+// was full.
+type AsyncOutput struct {
+	w *asyncWriter
+}
+
+// Dropped returns the number of records this output has discarded so far.
+// Always zero for the Block policy.
+func (this *AsyncOutput) Dropped() uint64 {
+	return atomic.LoadUint64(&this.w.dropped)
+}
+
+// asyncMsg is either a pre-formatted record or a flush marker; keeping both
+// on the same channel preserves ordering between logged records and flushes.
+type asyncMsg struct {
+	data     []byte
+	level    Level
+	hasLevel bool
+	ack      chan struct{}
+}
+
+// asyncWriter wraps an io.Writer so Write only ever enqueues pre-formatted
+// bytes; a single background goroutine performs the (possibly slow) real
+// write, so a stuck sink only blocks Logger.Log callers once the buffer is
+// full and the policy is Block.
+type asyncWriter struct {
+	dst     io.Writer
+	policy  OverflowPolicy
+	ch      chan asyncMsg
+	dropped uint64
+	wg      sync.WaitGroup
+}
+
+func newAsyncWriter(dst io.Writer, opts AsyncOptions) *asyncWriter {
+	bufSize := opts.BufferSize
+	if bufSize <= 0 {
+		bufSize = defaultAsyncBufferSize
+	}
+	w := &asyncWriter{dst: dst, policy: opts.OverflowPolicy, ch: make(chan asyncMsg, bufSize)}
+	w.wg.Add(1)
+	go w.run()
+	return w
+}
+
+// Write implements io.Writer. p is expected to already be a fully formatted
+// record; it's copied since the caller may reuse its buffer.
+func (this *asyncWriter) Write(p []byte) (int, error) {
+	return this.enqueue(asyncMsg{data: append([]byte(nil), p...)})
+}
+
+// WriteLevel implements LeveledWriter, forwarding the level to the wrapped
+// writer if it also implements LeveledWriter (e.g. a syslog sink).
+func (this *asyncWriter) WriteLevel(level Level, p []byte) (int, error) {
+	return this.enqueue(asyncMsg{data: append([]byte(nil), p...), level: level, hasLevel: true})
+}
+
+func (this *asyncWriter) enqueue(msg asyncMsg) (int, error) {
+	n := len(msg.data)
+	switch this.policy {
+	case DropNewest:
+		select {
+		case this.ch <- msg:
+		default:
+			atomic.AddUint64(&this.dropped, 1)
+		}
+	case DropOldest:
+		for {
+			select {
+			case this.ch <- msg:
+				return n, nil
+			default:
+			}
+			select {
+			case old := <-this.ch:
+				if old.ack != nil {
+					close(old.ack) // the queue is being drained anyway; unblock any pending Flush
+				}
+				atomic.AddUint64(&this.dropped, 1)
+			default:
+			}
+		}
+	default: // Block
+		this.ch <- msg
+	}
+	return n, nil
+}
+
+func (this *asyncWriter) run() {
+	defer this.wg.Done()
+	lw, _ := this.dst.(LeveledWriter)
+	for msg := range this.ch {
+		if msg.ack != nil {
+			close(msg.ack)
+			continue
+		}
+		if lw != nil && msg.hasLevel {
+			lw.WriteLevel(msg.level, msg.data)
+		} else {
+			this.dst.Write(msg.data)
+		}
+	}
+}
+
+// flush blocks until every record enqueued before this call has been
+// written to the underlying writer.
+func (this *asyncWriter) flush() {
+	ack := make(chan struct{})
+	this.ch <- asyncMsg{ack: ack}
+	<-ack
+}
+
+// close flushes then stops the writer goroutine, joining it before returning.
+func (this *asyncWriter) close() {
+	this.flush()
+	close(this.ch)
+	this.wg.Wait()
+}
+
+// AddOutputAsync is like AddOutput, but writes to writer happen on a
+// dedicated background goroutine fed by a bounded buffer, so a slow sink
+// (a file on NFS, syslog, a network socket) can't block callers targeting
+// any other output. Formatting still happens on the caller's goroutine,
+// and only when level is actually met, so timestamps reflect the time Log
+// was called. Under the default Block policy, a caller logging to this
+// output can still be made to wait once the buffer fills - see Block.
+func (this *Logger) AddOutputAsync(writer io.Writer, level Level, fm Formatter, opts AsyncOptions) *AsyncOutput {
+	aw := newAsyncWriter(writer, opts)
+	this.core.mtx.Lock()
+	this.core.outputs = append(this.core.outputs, output{aw, level, fm, &sync.Mutex{}})
+	this.core.asyncWriters = append(this.core.asyncWriters, aw)
+	this.core.recalcMinLevel()
+	this.core.mtx.Unlock()
+	return &AsyncOutput{aw}
+}
+
+// Flush blocks until every async output has written out its buffered
+// records. Synchronous outputs need no flushing since they write inline.
+func (this *Logger) Flush() {
+	this.core.mtx.Lock()
+	writers := append([]*asyncWriter(nil), this.core.asyncWriters...)
+	this.core.mtx.Unlock()
+	for _, w := range writers {
+		w.flush()
+	}
+}
+
+// Close flushes and stops all async outputs, joining their goroutines.
+// Call it during shutdown to guarantee buffered records are delivered
+// before the program exits. Any Log call made during or after Close sees
+// those outputs removed rather than writing to a closed channel, so a
+// straggling log line degrades to "dropped" instead of panicking.
+func (this *Logger) Close() {
+	this.core.mtx.Lock()
+	writers := append([]*asyncWriter(nil), this.core.asyncWriters...)
+	this.core.asyncWriters = nil
+	this.core.outputs = removeAsyncOutputs(this.core.outputs, writers)
+	this.core.recalcMinLevel()
+	this.core.mtx.Unlock()
+	for _, w := range writers {
+		w.close()
+	}
+}
+
+// removeAsyncOutputs returns outputs with every entry whose writer is one
+// of writers dropped, without mutating the backing array of outputs.
+func removeAsyncOutputs(outputs []output, writers []*asyncWriter) []output {
+	if len(writers) == 0 {
+		return outputs
+	}
+	closed := make(map[*asyncWriter]bool, len(writers))
+	for _, w := range writers {
+		closed[w] = true
+	}
+	kept := make([]output, 0, len(outputs))
+	for _, o := range outputs {
+		if aw, ok := o.writer.(*asyncWriter); ok && closed[aw] {
+			continue
+		}
+		kept = append(kept, o)
+	}
+	return kept
+}
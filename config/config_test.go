@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	log "github.com/iketutg/go-log"
+)
+
+func TestConfigureLogLevelEnvOverridesConfiguredLevel(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "INFO")
+
+	logger, err := Configure(Config{Outputs: []OutputConfig{
+		{Name: "stdout", Level: "ERROR"},
+	}})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	if !logger.IsLevelEnabled(log.Levels.Info) {
+		t.Fatal("IsLevelEnabled(Info) = false, want true: LOG_LEVEL=INFO should override the configured ERROR level")
+	}
+}
+
+func TestConfigureWithoutLogLevelEnvUsesConfiguredLevel(t *testing.T) {
+	logger, err := Configure(Config{Outputs: []OutputConfig{
+		{Name: "stdout", Level: "ERROR"},
+	}})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	if logger.IsLevelEnabled(log.Levels.Info) {
+		t.Fatal("IsLevelEnabled(Info) = true, want false: configured level is ERROR and LOG_LEVEL isn't set")
+	}
+	if !logger.IsLevelEnabled(log.Levels.Error) {
+		t.Fatal("IsLevelEnabled(Error) = false, want true")
+	}
+}
+
+// TestConfigureNetworkOutputIsAsync guards against a tcp:// or udp:// output
+// being wired through the synchronous AddOutput: Close must drain, stop and
+// remove it like any other async output (so Info is no longer enabled), and
+// logging afterwards must not panic. A synchronous output would survive
+// Close and keep Info enabled.
+func TestConfigureNetworkOutputIsAsync(t *testing.T) {
+	logger, err := Configure(Config{Outputs: []OutputConfig{
+		{Name: "tcp://127.0.0.1:0", Level: "INFO"},
+	}})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	logger.Info("hello")
+	logger.Close()
+
+	if logger.IsLevelEnabled(log.Levels.Info) {
+		t.Fatal("IsLevelEnabled(Info) = true after Close, want false: the network output should be async and removed by Close")
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Info after Close panicked: %v", r)
+		}
+	}()
+	logger.Info("after close")
+}
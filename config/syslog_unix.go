@@ -0,0 +1,14 @@
+//go:build !windows
+
+package config
+
+import (
+	"io"
+	stdsyslog "log/syslog"
+
+	"github.com/iketutg/go-log/sinks"
+)
+
+func newSyslogWriter(tag string) (io.Writer, error) {
+	return sinks.NewLocalSyslogSink(stdsyslog.LOG_USER, tag)
+}
@@ -0,0 +1,12 @@
+//go:build windows
+
+package config
+
+import (
+	"errors"
+	"io"
+)
+
+func newSyslogWriter(tag string) (io.Writer, error) {
+	return nil, errors.New("config: syslog:// outputs aren't supported on windows")
+}
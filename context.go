@@ -0,0 +1,51 @@
+package log
+
+import "context"
+
+// loggerContextKey, traceIDContextKey and spanIDContextKey are distinct,
+// unexported types so they can't collide with keys set by other packages.
+type loggerContextKey struct{}
+type traceIDContextKey struct{}
+type spanIDContextKey struct{}
+
+// TraceIDKey and SpanIDKey are the context.Context keys WithContext reads
+// well-known tracing identifiers from, e.g.
+// ctx = context.WithValue(ctx, log.TraceIDKey, traceID).
+var (
+	TraceIDKey interface{} = traceIDContextKey{}
+	SpanIDKey  interface{} = spanIDContextKey{}
+)
+
+// NewContext returns a copy of ctx carrying l, retrievable with FromContext.
+// Use it to thread a request-scoped Logger through a handler chain.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+// FromContext returns the Logger stored in ctx by NewContext. If none was
+// stored, it returns a Logger with no outputs, which silently discards
+// everything logged to it - a safe default for code that might run outside
+// a request.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(loggerContextKey{}).(*Logger); ok {
+		return l
+	}
+	return New()
+}
+
+// WithContext returns a child Logger (see With) with trace_id/span_id
+// fields populated from ctx, for whichever of TraceIDKey/SpanIDKey it
+// carries. If ctx carries neither, it returns this Logger itself.
+func (this *Logger) WithContext(ctx context.Context) *Logger {
+	fields := Fields{}
+	if v := ctx.Value(TraceIDKey); v != nil {
+		fields["trace_id"] = v
+	}
+	if v := ctx.Value(SpanIDKey); v != nil {
+		fields["span_id"] = v
+	}
+	if len(fields) == 0 {
+		return this
+	}
+	return this.With(fields)
+}
@@ -0,0 +1,45 @@
+package log
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromContextWithoutStoredLoggerDiscardsSilently(t *testing.T) {
+	l := FromContext(context.Background())
+	if l == nil {
+		t.Fatal("FromContext returned nil")
+	}
+	l.Info("should not panic or write anywhere")
+}
+
+func TestNewContextRoundTrips(t *testing.T) {
+	want := New()
+	ctx := NewContext(context.Background(), want)
+
+	if got := FromContext(ctx); got != want {
+		t.Fatalf("FromContext returned %p, want %p", got, want)
+	}
+}
+
+func TestWithContextPopulatesTraceAndSpan(t *testing.T) {
+	base := New()
+	ctx := context.WithValue(context.Background(), TraceIDKey, "trace-1")
+	ctx = context.WithValue(ctx, SpanIDKey, "span-1")
+
+	child := base.WithContext(ctx)
+	if child.fields["trace_id"] != "trace-1" {
+		t.Fatalf("trace_id = %v, want trace-1", child.fields["trace_id"])
+	}
+	if child.fields["span_id"] != "span-1" {
+		t.Fatalf("span_id = %v, want span-1", child.fields["span_id"])
+	}
+}
+
+func TestWithContextWithoutTracingKeysReturnsSameLogger(t *testing.T) {
+	base := New()
+	child := base.WithContext(context.Background())
+	if child != base {
+		t.Fatalf("WithContext with no tracing keys allocated a new Logger, want the same instance back")
+	}
+}
@@ -0,0 +1,111 @@
+package log
+
+import "fmt"
+
+// Fields is a set of key/value pairs attached to a log record. nil and the
+// empty Fields{} are both valid and carry no context.
+type Fields map[string]interface{}
+
+// Entry carries a Logger together with the Fields accumulated on it via
+// WithField(s). Entries are immutable: every WithField(s) call returns a
+// new Entry, so it's safe to keep a base Entry around and branch off it
+// from multiple goroutines.
+type Entry struct {
+	logger *Logger
+	fields Fields
+}
+
+// WithFields starts a structured log record carrying the given Fields.
+// Use it together with one of the Entry convenience methods, e.g.
+// logger.WithFields(log.Fields{"req_id": id}).Info("handled request").
+func (this *Logger) WithFields(fields Fields) *Entry {
+	return (&Entry{logger: this}).WithFields(fields)
+}
+
+// WithField is a shorthand for WithFields with a single key/value pair.
+func (this *Logger) WithField(key string, value interface{}) *Entry {
+	return this.WithFields(Fields{key: value})
+}
+
+// WithFields returns a new Entry with fields merged on top of this one's.
+// Keys present in both keep the new value.
+func (this *Entry) WithFields(fields Fields) *Entry {
+	merged := make(Fields, len(this.fields)+len(fields))
+	for k, v := range this.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return &Entry{logger: this.logger, fields: merged}
+}
+
+// WithField is a shorthand for WithFields with a single key/value pair.
+func (this *Entry) WithField(key string, value interface{}) *Entry {
+	return this.WithFields(Fields{key: value})
+}
+
+// Log writes msg at the given level with this Entry's fields, merged on
+// top of the underlying Logger's own baseline fields (if any), attached.
+func (this *Entry) Log(level Level, msg string) {
+	if !this.logger.IsLevelEnabled(level) {
+		return
+	}
+	this.logger.logEntry(level, msg, mergeFields(this.logger.fields, this.fields))
+}
+
+// Logf is a formatted wrapper around Log.
+func (this *Entry) Logf(level Level, format string, v ...interface{}) {
+	if !this.logger.IsLevelEnabled(level) {
+		return
+	}
+	this.logger.logEntry(level, fmt.Sprintf(format+"\n", v...), mergeFields(this.logger.fields, this.fields))
+}
+
+// Convenience function
+func (this *Entry) Trace(format string, v ...interface{}) {
+	if !this.logger.IsLevelEnabled(Levels.Trace) {
+		return
+	}
+	this.logger.logEntry(Levels.Trace, fmt.Sprintf(format+"\n", v...), mergeFields(this.logger.fields, this.fields))
+}
+
+// Convenience function
+func (this *Entry) Debug(format string, v ...interface{}) {
+	if !this.logger.IsLevelEnabled(Levels.Debug) {
+		return
+	}
+	this.logger.logEntry(Levels.Debug, fmt.Sprintf(format+"\n", v...), mergeFields(this.logger.fields, this.fields))
+}
+
+// Convenience function
+func (this *Entry) Info(format string, v ...interface{}) {
+	if !this.logger.IsLevelEnabled(Levels.Info) {
+		return
+	}
+	this.logger.logEntry(Levels.Info, fmt.Sprintf(format+"\n", v...), mergeFields(this.logger.fields, this.fields))
+}
+
+// Convenience function
+func (this *Entry) Warning(format string, v ...interface{}) {
+	if !this.logger.IsLevelEnabled(Levels.Warning) {
+		return
+	}
+	this.logger.logEntry(Levels.Warning, fmt.Sprintf(format+"\n", v...), mergeFields(this.logger.fields, this.fields))
+}
+
+// Convenience function
+func (this *Entry) Error(format string, v ...interface{}) {
+	if !this.logger.IsLevelEnabled(Levels.Error) {
+		return
+	}
+	this.logger.logEntry(Levels.Error, fmt.Sprintf(format+"\n", v...), mergeFields(this.logger.fields, this.fields))
+}
+
+// Convenience function, will not terminate the program
+func (this *Entry) Fatal(format string, v ...interface{}) {
+	if !this.logger.IsLevelEnabled(Levels.Fatal) {
+		return
+	}
+	this.logger.logEntry(Levels.Fatal, fmt.Sprintf(format+"\n", v...), mergeFields(this.logger.fields, this.fields))
+}
@@ -0,0 +1,55 @@
+package log
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStdFormatterFormatEntryQuotesFieldsWithWhitespace(t *testing.T) {
+	f := StdFormatter{}
+	out := string(f.FormatEntry(Levels.Info, "hello", Fields{
+		"plain": "value",
+		"space": "has space",
+	}, time.Now(), ""))
+
+	if !strings.Contains(out, `plain=value`) {
+		t.Fatalf("output %q missing unquoted plain=value", out)
+	}
+	if !strings.Contains(out, `space="has space"`) {
+		t.Fatalf("output %q missing quoted space=\"has space\"", out)
+	}
+}
+
+func TestStdFormatterFormatEntryFieldsAreSorted(t *testing.T) {
+	f := StdFormatter{}
+	out := string(f.FormatEntry(Levels.Info, "hello", Fields{
+		"zebra": 1,
+		"alpha": 2,
+	}, time.Now(), ""))
+
+	if strings.Index(out, "alpha") > strings.Index(out, "zebra") {
+		t.Fatalf("output %q: fields not rendered in sorted order", out)
+	}
+}
+
+func TestJSONFormatterRendersFieldsAndOmitsEmptyCaller(t *testing.T) {
+	f := JSONFormatter{}
+	out := f.FormatEntry(Levels.Warning, "hello", Fields{"k": "v"}, time.Now(), "")
+
+	var rec map[string]interface{}
+	if err := json.Unmarshal(out, &rec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if rec["msg"] != "hello" {
+		t.Fatalf("msg = %v, want hello", rec["msg"])
+	}
+	if _, ok := rec["caller"]; ok {
+		t.Fatalf("caller present in record %v, want omitted when empty", rec)
+	}
+	fields, ok := rec["fields"].(map[string]interface{})
+	if !ok || fields["k"] != "v" {
+		t.Fatalf("fields = %v, want {k: v}", rec["fields"])
+	}
+}
@@ -0,0 +1,72 @@
+package log
+
+import (
+	"fmt"
+	"io"
+)
+
+// Hook lets callers react to log records as a side effect - counting
+// error-level logs in Prometheus, forwarding Fatal to an alerting system,
+// shipping structured records to an external aggregator - without
+// inventing a fake io.Writer. Fire runs after the record has gone to every
+// accepting output; for async outputs (whose Write only enqueues) that
+// means Fire effectively runs in parallel with the real write.
+//
+// Fire runs on the logging goroutine, with the Logger's mutex already
+// released, so a slow Fire only delays the caller that triggered it, not
+// every other goroutine logging on the same Logger. It's still run
+// synchronously relative to that one call: a Hook that must not add
+// latency to its caller (e.g. a network call) should hand off to its own
+// goroutine/queue inside Fire and return promptly.
+type Hook interface {
+	// Levels returns the levels this hook wants to be notified about. An
+	// empty slice means "every level".
+	Levels() []Level
+	Fire(level Level, msg string, fields Fields) error
+}
+
+// AddHook registers hook on this Logger and everything sharing its core
+// via With.
+func (this *Logger) AddHook(hook Hook) {
+	this.core.mtx.Lock()
+	this.core.hooks = append(this.core.hooks, hook)
+	this.core.recalcMinLevel()
+	this.core.mtx.Unlock()
+}
+
+// SetHookErrorWriter sets where errors returned by Hook.Fire get reported.
+// By default they're swallowed silently: a broken hook must never break
+// the logger.
+func (this *Logger) SetHookErrorWriter(w io.Writer) {
+	this.core.mtx.Lock()
+	this.core.hookErrWriter = w
+	this.core.mtx.Unlock()
+}
+
+// fireHooks runs every hook in hooks that's interested in level, reporting
+// any error to errWriter (if set) rather than propagating it. It takes a
+// plain slice/writer rather than a *loggerCore so callers fire it after
+// releasing the core's mutex.
+func fireHooks(hooks []Hook, errWriter io.Writer, level Level, msg string, fields Fields) {
+	for _, hook := range hooks {
+		if !hookWantsLevel(hook, level) {
+			continue
+		}
+		if err := hook.Fire(level, msg, fields); err != nil && errWriter != nil {
+			fmt.Fprintf(errWriter, "log: hook error: %v\n", err)
+		}
+	}
+}
+
+func hookWantsLevel(hook Hook, level Level) bool {
+	levels := hook.Levels()
+	if len(levels) == 0 {
+		return true
+	}
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
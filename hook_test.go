@@ -0,0 +1,76 @@
+package log
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+type slowHook struct {
+	sleep time.Duration
+}
+
+func (this slowHook) Levels() []Level { return nil }
+
+func (this slowHook) Fire(level Level, msg string, fields Fields) error {
+	time.Sleep(this.sleep)
+	return nil
+}
+
+// TestHooksDoNotSerializeLoggers asserts that a slow Hook.Fire only delays
+// the goroutine that triggered it, not every other goroutine logging on
+// the same Logger - logEntry must release the core mutex before firing hooks.
+func TestHooksDoNotSerializeLoggers(t *testing.T) {
+	l := New()
+	l.AddOutput(io.Discard, Levels.Info, StdFormatter{})
+	l.AddHook(slowHook{sleep: 200 * time.Millisecond})
+
+	const n = 5
+	var wg sync.WaitGroup
+	wg.Add(n)
+	start := time.Now()
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			l.Info("hello")
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if elapsed > 350*time.Millisecond {
+		t.Fatalf("hooks appear to serialize callers: %d goroutines took %v, want ~200ms", n, elapsed)
+	}
+}
+
+type countingHook struct {
+	mtx   sync.Mutex
+	count int
+	want  []Level
+}
+
+func (this *countingHook) Levels() []Level { return this.want }
+
+func (this *countingHook) Fire(level Level, msg string, fields Fields) error {
+	this.mtx.Lock()
+	this.count++
+	this.mtx.Unlock()
+	return nil
+}
+
+func TestHookLevelFiltering(t *testing.T) {
+	l := New()
+	l.AddOutput(io.Discard, Levels.Trace, StdFormatter{})
+	hook := &countingHook{want: []Level{Levels.Error, Levels.Fatal}}
+	l.AddHook(hook)
+
+	l.Info("ignored")
+	l.Warning("ignored")
+	l.Error("counted")
+	l.Fatal("counted")
+
+	if hook.count != 2 {
+		t.Fatalf("hook.count = %d, want 2", hook.count)
+	}
+}
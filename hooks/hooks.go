@@ -0,0 +1,53 @@
+// Package hooks provides reference log.Hook implementations: a per-level
+// metrics counter and a thin wrapper around a plain callback function.
+package hooks
+
+import log "github.com/iketutg/go-log"
+
+// Counter is satisfied by prometheus.Counter (and anything else exposing
+// Inc()), so CounterHook can plug into a metrics registry without this
+// package depending on the prometheus client library.
+type Counter interface {
+	Inc()
+}
+
+// CounterHook increments a per-level Counter every time a matching record
+// is logged. Levels with no entry in Counters are ignored.
+type CounterHook struct {
+	Counters map[log.Level]Counter
+}
+
+// Levels implements log.Hook.
+func (this CounterHook) Levels() []log.Level {
+	levels := make([]log.Level, 0, len(this.Counters))
+	for l := range this.Counters {
+		levels = append(levels, l)
+	}
+	return levels
+}
+
+// Fire implements log.Hook.
+func (this CounterHook) Fire(level log.Level, msg string, fields log.Fields) error {
+	if c, ok := this.Counters[level]; ok {
+		c.Inc()
+	}
+	return nil
+}
+
+// CallbackHook adapts a plain function to log.Hook, for ad-hoc use without
+// defining a new type.
+type CallbackHook struct {
+	// For is which levels to invoke Fn for. Empty means every level.
+	For []log.Level
+	Fn  func(level log.Level, msg string, fields log.Fields) error
+}
+
+// Levels implements log.Hook.
+func (this CallbackHook) Levels() []log.Level {
+	return this.For
+}
+
+// Fire implements log.Hook.
+func (this CallbackHook) Fire(level log.Level, msg string, fields log.Fields) error {
+	return this.Fn(level, msg, fields)
+}
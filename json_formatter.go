@@ -0,0 +1,61 @@
+package log
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONFormatter renders every log record as a single line JSON object,
+// which plays nicely with log aggregators (ELK, Loki, CloudWatch, ...).
+// It implements both Formatter and StructuredFormatter: used without
+// fields it still produces a valid record, just without a "fields" key.
+type JSONFormatter struct {
+	// TimeFormat overrides the default RFC3339 timestamp (millisecond
+	// precision). Leave empty to use the default.
+	TimeFormat string
+}
+
+// defaultJSONTimeFormat is RFC3339 with millisecond precision.
+const defaultJSONTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+type jsonRecord struct {
+	Time   string `json:"time"`
+	Level  string `json:"level"`
+	Msg    string `json:"msg"`
+	Caller string `json:"caller,omitempty"`
+	Fields Fields `json:"fields,omitempty"`
+}
+
+// Format implements Formatter. It has no caller or fields to work with, so
+// it emits a record with neither.
+func (this JSONFormatter) Format(level Level, msg string) []byte {
+	return this.FormatEntry(level, msg, nil, time.Now(), "")
+}
+
+// FormatEntry implements StructuredFormatter.
+func (this JSONFormatter) FormatEntry(level Level, msg string, fields Fields, t time.Time, caller string) []byte {
+	timeFormat := this.TimeFormat
+	if timeFormat == "" {
+		timeFormat = defaultJSONTimeFormat
+	}
+
+	slevel, ok := levelStrings[level]
+	if !ok {
+		slevel = strconv.Itoa(int(level))
+	}
+
+	rec := jsonRecord{
+		Time:   t.Format(timeFormat),
+		Level:  slevel,
+		Msg:    strings.TrimSuffix(msg, "\n"),
+		Caller: shortenCaller(caller),
+		Fields: fields,
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return []byte(err.Error() + "\n")
+	}
+	return append(b, '\n')
+}
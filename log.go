@@ -13,6 +13,7 @@ import (
 	"io"
 	"log"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -70,21 +71,85 @@ type Formatter interface {
 	Format(Level, string) []byte
 }
 
+// StructuredFormatter is an optional extension of Formatter, implemented by
+// formatters that can render the extra context carried by a Fields-bearing
+// Entry: the merged fields, the time the record was logged and the caller
+// location. Formatters which only implement Formatter keep working as
+// before; they simply won't see fields attached via WithField(s).
+type StructuredFormatter interface {
+	FormatEntry(level Level, msg string, fields Fields, t time.Time, caller string) []byte
+}
+
+// LeveledWriter is an optional extension of io.Writer for outputs that need
+// to know the Level of the record they're about to write, e.g. a syslog
+// sink mapping Level to a syslog severity. Outputs that only implement
+// io.Writer keep working; they just don't see the level.
+type LeveledWriter interface {
+	io.Writer
+	WriteLevel(level Level, p []byte) (int, error)
+}
+
 type output struct {
 	writer io.Writer
 	level  Level
 	fmt    Formatter
+	// mtx serializes writes to this output without serializing writes to
+	// any other output: it's per-output rather than core.mtx so a slow or
+	// stuck writer only stalls callers targeting it, not every goroutine
+	// logging on the Logger. A pointer so copying an output (e.g. taking a
+	// snapshot of core.outputs) never copies lock state.
+	mtx *sync.Mutex
+}
+
+// loggerCore holds everything a family of Logger values built via With share:
+// the outputs, their async writers and the cached minLevel. Splitting it out
+// of Logger lets With return a child Logger that's just a pointer to the
+// same core plus its own baseline Fields, with no locking or copying of outputs.
+type loggerCore struct {
+	mtx           sync.Mutex
+	outputs       []output
+	asyncWriters  []*asyncWriter
+	hooks         []Hook
+	hookErrWriter io.Writer
+	minLevel      Level
 }
 
 // The Logger
 type Logger struct {
-	mtx     sync.Mutex
-	outputs []output
+	core   *loggerCore
+	fields Fields
 }
 
 // Instantiate a new Logger
 func New() *Logger {
-	return &Logger{sync.Mutex{}, make([]output, 0)}
+	return &Logger{core: &loggerCore{outputs: make([]output, 0), minLevel: noOutputsLevel}}
+}
+
+// With returns a child Logger that shares this Logger's outputs but has
+// fields merged into every record it writes. It's cheap: no outputs are
+// copied, only the (small) field map. Useful for request-scoped loggers,
+// e.g. handler.logger = base.With(log.Fields{"component": "handler"}).
+func (this *Logger) With(fields Fields) *Logger {
+	return &Logger{core: this.core, fields: mergeFields(this.fields, fields)}
+}
+
+// mergeFields returns extra merged on top of base, without mutating either.
+// It avoids allocating when one side is empty.
+func mergeFields(base, extra Fields) Fields {
+	if len(extra) == 0 {
+		return base
+	}
+	if len(base) == 0 {
+		return extra
+	}
+	merged := make(Fields, len(base)+len(extra))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range extra {
+		merged[k] = v
+	}
+	return merged
 }
 
 // Standard Formatter
@@ -147,6 +212,93 @@ func (this StdFormatter) Format(level Level, msg string) []byte {
 	return []byte(strings.Join(out, " "))
 }
 
+// FormatEntry implements StructuredFormatter. It renders the same way as
+// Format, plus the accumulated fields as space-separated, quoted
+// "key=value" pairs inserted right before the message.
+func (this StdFormatter) FormatEntry(level Level, msg string, fields Fields, t time.Time, caller string) []byte {
+	var slevel string
+	var ok bool
+	var out []string
+
+	if this.Flag&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		if this.Flag&log.Ldate != 0 {
+			out = append(out, fmt.Sprintf("%v-%02d-%02d", t.Year(), t.Month(), t.Day()))
+		}
+		if this.Flag&(log.Lmicroseconds) != 0 {
+			out = append(out, fmt.Sprintf("%02d:%02d:%02d.%06d", t.Hour(), t.Minute(), t.Second(), t.Nanosecond()/1000000))
+		} else if this.Flag&(log.Ltime) != 0 {
+			out = append(out, fmt.Sprintf("%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second()))
+		}
+	}
+
+	if this.Colored {
+		slevel, ok = levelCStrings[level]
+	} else {
+		slevel, ok = levelStrings[level]
+	}
+	if !ok {
+		slevel = strconv.Itoa(int(level))
+	}
+	out = append(out, slevel)
+
+	out = append(out, this.Prefix)
+
+	if this.Flag&(log.Lshortfile|log.Llongfile) != 0 {
+		if caller == "" {
+			out = append(out, "???")
+		} else {
+			if this.Flag&log.Lshortfile != 0 {
+				caller = shortenCaller(caller)
+			}
+			out = append(out, caller)
+		}
+	}
+
+	if len(fields) > 0 {
+		out = append(out, formatFields(fields))
+	}
+
+	out = append(out, msg)
+	return []byte(strings.Join(out, " "))
+}
+
+// shortenCaller trims a "/full/path/file.go:line" caller string down to
+// "file.go:line", mirroring log.Lshortfile.
+func shortenCaller(caller string) string {
+	for i := len(caller) - 1; i > 0; i-- {
+		if caller[i] == '/' {
+			return caller[i+1:]
+		}
+	}
+	return caller
+}
+
+// formatFields renders fields as sorted, space-separated key=value pairs,
+// quoting values that contain whitespace so the line stays easy to parse.
+func formatFields(fields Fields) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+formatFieldValue(fields[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatFieldValue stringifies a single field value, quoting it when it
+// contains characters that would otherwise break key=value parsing.
+func formatFieldValue(v interface{}) string {
+	s := fmt.Sprintf("%v", v)
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
 /* LOGGER
  * ------
  */
@@ -156,56 +308,191 @@ func (this StdFormatter) Format(level Level, msg string) []byte {
 // if you pass Warning for level, all logs of type
 // Warning, Error, and Fatal would be logged to this output.
 func (this *Logger) AddOutput(writer io.Writer, level Level, fm Formatter) {
-	this.mtx.Lock()
-	this.outputs = append(this.outputs, output{writer, level, fm})
-	this.mtx.Unlock()
+	this.core.mtx.Lock()
+	this.core.outputs = append(this.core.outputs, output{writer, level, fm, &sync.Mutex{}})
+	this.core.recalcMinLevel()
+	this.core.mtx.Unlock()
+}
+
+// noOutputsLevel is the minLevel of a Logger with no outputs or hooks: nothing can
+// satisfy it, so IsLevelEnabled correctly reports false for every level.
+const noOutputsLevel Level = 255
+
+// recalcMinLevel refreshes minLevel from the current outputs and hooks.
+// Callers must hold mtx.
+func (this *loggerCore) recalcMinLevel() {
+	min := noOutputsLevel
+	has := false
+	consider := func(l Level) {
+		if !has || l < min {
+			min = l
+			has = true
+		}
+	}
+	for _, o := range this.outputs {
+		consider(o.level)
+	}
+	for _, h := range this.hooks {
+		levels := h.Levels()
+		if len(levels) == 0 {
+			consider(Levels.Trace) // no filter: wants every level
+			continue
+		}
+		for _, l := range levels {
+			consider(l)
+		}
+	}
+	this.minLevel = min
+}
+
+// IsLevelEnabled reports whether level would be written to at least one
+// output or passed to at least one hook, so callers can skip expensive
+// message construction entirely.
+func (this *Logger) IsLevelEnabled(level Level) bool {
+	this.core.mtx.Lock()
+	min := this.core.minLevel
+	this.core.mtx.Unlock()
+	return level >= min
 }
 
 // Logs a message for the given level. Most callers will likely
 // prefer to use one of the provided convenience functions (Debug, Info...).
 func (this *Logger) Log(level Level, msg string) {
-	this.mtx.Lock()
-	defer this.mtx.Unlock()
-	for _, output := range this.outputs {
-		if output.level <= level {
-			output.writer.Write(output.fmt.Format(level, msg))
+	if !this.IsLevelEnabled(level) {
+		return
+	}
+	this.logEntry(level, msg, this.fields)
+}
+
+// Logl lazily builds the message, only when level is enabled. Use it to
+// avoid paying for expensive serialization (e.g. dumping a large struct
+// for a Trace log) on a level nobody is listening to:
+//
+//	logger.Logl(log.Levels.Trace, func() string { return fmt.Sprintf("state=%+v", big) })
+func (this *Logger) Logl(level Level, fn func() string) {
+	if !this.IsLevelEnabled(level) {
+		return
+	}
+	this.logEntry(level, fn(), this.fields)
+}
+
+// logEntry is the shared dispatch path for every exported logging method
+// (Log, Logl, Logf, Trace, Debug, ... on both Logger and Entry). Every one
+// of those calls logEntry directly rather than through one another, so
+// callerLocation's depth is the same regardless of which was used. Time
+// and caller are only computed once per call, and only when at least one
+// output's formatter actually wants them.
+func (this *Logger) logEntry(level Level, msg string, fields Fields) {
+	this.core.mtx.Lock()
+	matched := make([]output, 0, len(this.core.outputs))
+	for _, o := range this.core.outputs {
+		if o.level <= level {
+			matched = append(matched, o)
 		}
 	}
+	// Snapshot the hooks (and where to report their errors) while we hold
+	// the lock, then release it before writing to outputs or firing hooks:
+	// a blocked or slow output/hook must only delay the caller targeting
+	// it, never every other goroutine logging on this Logger.
+	hooks := append([]Hook(nil), this.core.hooks...)
+	hookErrWriter := this.core.hookErrWriter
+	this.core.mtx.Unlock()
+
+	var t time.Time
+	var caller string
+	var haveContext bool
+	for _, o := range matched {
+		var formatted []byte
+		if sf, ok := o.fmt.(StructuredFormatter); ok {
+			if !haveContext {
+				t = time.Now()
+				caller = callerLocation(3) // 3: calldepth to the exported method's caller
+				haveContext = true
+			}
+			formatted = sf.FormatEntry(level, msg, fields, t, caller)
+		} else {
+			formatted = o.fmt.Format(level, msg)
+		}
+
+		// o.mtx, not core.mtx: writing to this output (which may block,
+		// e.g. an async output's full Block-policy buffer) must not stall
+		// writes to any other output.
+		o.mtx.Lock()
+		if lw, ok := o.writer.(LeveledWriter); ok {
+			lw.WriteLevel(level, formatted)
+		} else {
+			o.writer.Write(formatted)
+		}
+		o.mtx.Unlock()
+	}
+
+	fireHooks(hooks, hookErrWriter, level, msg, fields)
+}
+
+// Returns "file:line" (full path) for the given calldepth, or "" if it
+// can't be determined. Formatters trim it down to a short filename
+// themselves when asked to.
+func callerLocation(calldepth int) string {
+	if _, file, line, ok := runtime.Caller(calldepth); ok {
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+	return ""
 }
 
 // Logs a formatted message message for the given level.
 // Wrapper around Log method
 func (this *Logger) Logf(level Level, format string, v ...interface{}) {
-	this.Log(level, fmt.Sprintf(format+"\n", v...))
+	if !this.IsLevelEnabled(level) {
+		return
+	}
+	this.logEntry(level, fmt.Sprintf(format+"\n", v...), this.fields)
 }
 
 // Convenience function
 func (this *Logger) Trace(format string, v ...interface{}) {
 	// TODO: split the string
-	this.Log(Levels.Trace, fmt.Sprintf(format+"\n", v...))
+	if !this.IsLevelEnabled(Levels.Trace) {
+		return
+	}
+	this.logEntry(Levels.Trace, fmt.Sprintf(format+"\n", v...), this.fields)
 }
 
 // Convenience function
 func (this *Logger) Debug(format string, v ...interface{}) {
-	this.Log(Levels.Debug, fmt.Sprintf(format+"\n", v...))
+	if !this.IsLevelEnabled(Levels.Debug) {
+		return
+	}
+	this.logEntry(Levels.Debug, fmt.Sprintf(format+"\n", v...), this.fields)
 }
 
 // Convenience function
 func (this *Logger) Info(format string, v ...interface{}) {
-	this.Log(Levels.Info, fmt.Sprintf(format+"\n", v...))
+	if !this.IsLevelEnabled(Levels.Info) {
+		return
+	}
+	this.logEntry(Levels.Info, fmt.Sprintf(format+"\n", v...), this.fields)
 }
 
 // Convenience function
 func (this *Logger) Warning(format string, v ...interface{}) {
-	this.Log(Levels.Warning, fmt.Sprintf(format+"\n", v...))
+	if !this.IsLevelEnabled(Levels.Warning) {
+		return
+	}
+	this.logEntry(Levels.Warning, fmt.Sprintf(format+"\n", v...), this.fields)
 }
 
 // Convenience function
 func (this *Logger) Error(format string, v ...interface{}) {
-	this.Log(Levels.Error, fmt.Sprintf(format+"\n", v...))
+	if !this.IsLevelEnabled(Levels.Error) {
+		return
+	}
+	this.logEntry(Levels.Error, fmt.Sprintf(format+"\n", v...), this.fields)
 }
 
 // Convenience function, will not terminate the program
 func (this *Logger) Fatal(format string, v ...interface{}) {
-	this.Log(Levels.Fatal, fmt.Sprintf(format+"\n", v...))
+	if !this.IsLevelEnabled(Levels.Fatal) {
+		return
+	}
+	this.logEntry(Levels.Fatal, fmt.Sprintf(format+"\n", v...), this.fields)
 }
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+type jsonOut struct {
+	Caller string `json:"caller"`
+}
+
+// expectCallerAtThisLine calls logCall (which must be a one-line closure
+// wrapping a single logging call) and asserts the record buf received
+// reports its caller as the source line of this very call - i.e. the
+// closure's call site, not some frame inside package log.
+func expectCallerAtThisLine(t *testing.T, buf *bytes.Buffer, logCall func()) {
+	t.Helper()
+	_, file, line, _ := runtime.Caller(1)
+	logCall()
+
+	var rec jsonOut
+	if err := json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := fmt.Sprintf("%s:%d", shortenCaller(file), line)
+	if rec.Caller != want {
+		t.Fatalf("caller = %q, want %q", rec.Caller, want)
+	}
+}
+
+// TestCallerLocationDirectLog guards against callerLocation using a depth
+// that's only correct for the Trace/Debug/... convenience methods: calling
+// the exported Log method directly must report the caller's own line, not
+// a frame inside the logging package.
+func TestCallerLocationDirectLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.AddOutput(&buf, Levels.Trace, JSONFormatter{})
+
+	expectCallerAtThisLine(t, &buf, func() { l.Log(Levels.Info, "hello") })
+}
+
+// TestCallerLocationConvenienceMethod guards against a regression in the
+// opposite direction: the convenience methods must keep reporting their
+// caller's line too.
+func TestCallerLocationConvenienceMethod(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.AddOutput(&buf, Levels.Trace, JSONFormatter{})
+
+	expectCallerAtThisLine(t, &buf, func() { l.Info("hello") })
+}
+
+// TestCallerLocationEntryLogAndConvenience exercises the Entry call paths
+// the same way, since they have their own (previously divergent) depths.
+func TestCallerLocationEntryLogAndConvenience(t *testing.T) {
+	var buf bytes.Buffer
+	l := New()
+	l.AddOutput(&buf, Levels.Trace, JSONFormatter{})
+	e := l.WithField("req_id", "abc")
+
+	expectCallerAtThisLine(t, &buf, func() { e.Log(Levels.Info, "hello") })
+
+	buf.Reset()
+	expectCallerAtThisLine(t, &buf, func() { e.Info("hello") })
+}
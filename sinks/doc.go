@@ -0,0 +1,12 @@
+// Package sinks provides ready-to-use io.Writer outputs to plug into
+// log.Logger.AddOutput: a syslog sink, a size/time-rotating file, and a
+// reconnecting network sink. Each sink is safe for concurrent Write calls
+// on its own, which matters if it's also registered on more than one
+// Logger or wrapped with log.Logger.AddOutputAsync.
+//
+// Wrapping a sink in AddOutputAsync is safe and recommended for slow sinks
+// (a file on NFS, a remote syslog server, a flaky network socket): writes
+// then happen on a dedicated goroutine instead of blocking the logging
+// caller. SyslogSink implements log.LeveledWriter so its per-message
+// severity survives that wrapping.
+package sinks
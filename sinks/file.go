@@ -0,0 +1,184 @@
+package sinks
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotatingFile is an io.Writer that rotates the underlying file once it
+// exceeds MaxSizeBytes or MaxAge, keeping at most MaxBackups rotated files
+// (optionally gzip-compressed). The zero value only rotates when told to
+// via an explicit Rotate call.
+type RotatingFile struct {
+	// Path is the file written to; rotated files are written alongside it
+	// with a timestamp suffix (and a .gz suffix when Compress is set).
+	Path string
+	// MaxSizeBytes rotates the file once a write would push it past this
+	// size. Zero disables size-based rotation.
+	MaxSizeBytes int64
+	// MaxAge rotates the file once it's older than this. Zero disables
+	// age-based rotation.
+	MaxAge time.Duration
+	// MaxBackups is how many rotated files to keep; the oldest beyond that
+	// are removed. Zero keeps them all.
+	MaxBackups int
+	// Compress gzips a file as soon as it's rotated.
+	Compress bool
+
+	mtx      sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// Write implements io.Writer, rotating first if needed.
+func (this *RotatingFile) Write(p []byte) (int, error) {
+	this.mtx.Lock()
+	defer this.mtx.Unlock()
+
+	if this.file == nil {
+		if err := this.openLocked(); err != nil {
+			return 0, err
+		}
+	}
+	if this.shouldRotateLocked(len(p)) {
+		if err := this.rotateLocked(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := this.file.Write(p)
+	this.size += int64(n)
+	return n, err
+}
+
+func (this *RotatingFile) shouldRotateLocked(next int) bool {
+	if this.MaxSizeBytes > 0 && this.size+int64(next) > this.MaxSizeBytes {
+		return true
+	}
+	if this.MaxAge > 0 && time.Since(this.openedAt) > this.MaxAge {
+		return true
+	}
+	return false
+}
+
+func (this *RotatingFile) openLocked() error {
+	this.size = 0
+	this.openedAt = time.Now()
+	if info, err := os.Stat(this.Path); err == nil {
+		this.size = info.Size()
+		this.openedAt = info.ModTime()
+	}
+	f, err := os.OpenFile(this.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	this.file = f
+	return nil
+}
+
+// Rotate closes the current file, moves it aside (compressing it if
+// Compress is set) and opens a fresh one, pruning old backups beyond
+// MaxBackups. It's called automatically from Write, but is also exported
+// for callers that rotate on an external signal (e.g. SIGHUP).
+func (this *RotatingFile) Rotate() error {
+	this.mtx.Lock()
+	defer this.mtx.Unlock()
+	return this.rotateLocked()
+}
+
+func (this *RotatingFile) rotateLocked() error {
+	if this.file != nil {
+		this.file.Close()
+		this.file = nil
+	}
+	if _, err := os.Stat(this.Path); err == nil {
+		backup := fmt.Sprintf("%s.%s", this.Path, time.Now().Format("20060102T150405.000"))
+		if err := os.Rename(this.Path, backup); err != nil {
+			return err
+		}
+		if this.Compress {
+			if err := gzipAndRemove(backup); err != nil {
+				return err
+			}
+		}
+	}
+	if err := this.openLocked(); err != nil {
+		return err
+	}
+	return this.pruneBackupsLocked()
+}
+
+func gzipAndRemove(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		dst.Close()
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (this *RotatingFile) pruneBackupsLocked() error {
+	if this.MaxBackups <= 0 {
+		return nil
+	}
+	dir := filepath.Dir(this.Path)
+	base := filepath.Base(this.Path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, e := range entries {
+		name := e.Name()
+		if name != base && strings.HasPrefix(name, base+".") {
+			backups = append(backups, filepath.Join(dir, name))
+		}
+	}
+	// the timestamp suffix sorts lexically in chronological order
+	sort.Strings(backups)
+	if len(backups) <= this.MaxBackups {
+		return nil
+	}
+	for _, old := range backups[:len(backups)-this.MaxBackups] {
+		os.Remove(old)
+	}
+	return nil
+}
+
+// Close closes the current file.
+func (this *RotatingFile) Close() error {
+	this.mtx.Lock()
+	defer this.mtx.Unlock()
+	if this.file == nil {
+		return nil
+	}
+	err := this.file.Close()
+	this.file = nil
+	return err
+}
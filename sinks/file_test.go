@@ -0,0 +1,62 @@
+package sinks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRotatingFileRotatesOnSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	rf := &RotatingFile{Path: path, MaxSizeBytes: 10}
+	defer rf.Close()
+
+	if _, err := rf.Write([]byte("0123456789")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := rf.Write([]byte("more")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) < 2 {
+		t.Fatalf("dir has %d entries, want at least 2 (current file + a rotated backup)", len(entries))
+	}
+}
+
+func TestRotatingFilePrunesOldBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	rf := &RotatingFile{Path: path, MaxBackups: 1}
+	defer rf.Close()
+
+	if _, err := rf.Write([]byte("a")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := rf.Rotate(); err != nil {
+			t.Fatalf("Rotate: %v", err)
+		}
+		if _, err := rf.Write([]byte("a")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	backups := 0
+	for _, e := range entries {
+		if e.Name() != "app.log" {
+			backups++
+		}
+	}
+	if backups > 1 {
+		t.Fatalf("found %d backups, want at most MaxBackups=1", backups)
+	}
+}
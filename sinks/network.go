@@ -0,0 +1,148 @@
+package sinks
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// NetworkSink writes formatted records to a TCP or UDP endpoint. While
+// disconnected it buffers up to BacklogSize records (dropping the oldest
+// once full) and reconnects in the background.
+type NetworkSink struct {
+	// Network is "tcp" or "udp".
+	Network string
+	Addr    string
+	// DialTimeout bounds each (re)connection attempt. Defaults to 5s.
+	DialTimeout time.Duration
+	// RetryInterval is the wait between reconnection attempts. Defaults to 1s.
+	RetryInterval time.Duration
+	// BacklogSize bounds how many records are buffered while disconnected.
+	// Defaults to 1024.
+	BacklogSize int
+
+	mtx        sync.Mutex
+	conn       net.Conn
+	connecting bool
+	backlog    [][]byte
+	closed     bool
+}
+
+// Write implements io.Writer. It never blocks on the network: dialing and
+// reconnecting happen on a background goroutine, and writes to an
+// established connection are bounded by DialTimeout, so a failed, absent
+// or stalled connection buffers the record instead of blocking the caller.
+func (this *NetworkSink) Write(p []byte) (int, error) {
+	buf := append([]byte(nil), p...)
+
+	this.mtx.Lock()
+	defer this.mtx.Unlock()
+
+	if this.closed {
+		return 0, net.ErrClosed
+	}
+	if this.conn != nil {
+		this.conn.SetWriteDeadline(time.Now().Add(this.dialTimeout()))
+		if _, err := this.conn.Write(buf); err == nil {
+			return len(p), nil
+		}
+		this.conn.Close()
+		this.conn = nil
+	}
+	this.enqueueLocked(buf)
+	this.ensureConnectingLocked()
+	return len(p), nil
+}
+
+func (this *NetworkSink) dialTimeout() time.Duration {
+	if this.DialTimeout <= 0 {
+		return 5 * time.Second
+	}
+	return this.DialTimeout
+}
+
+// ensureConnectingLocked starts a background dial loop if one isn't
+// already in flight. Callers must hold mtx.
+func (this *NetworkSink) ensureConnectingLocked() {
+	if this.connecting || this.closed {
+		return
+	}
+	this.connecting = true
+	go this.connectLoop()
+}
+
+// connectLoop dials on its own goroutine, retrying every RetryInterval
+// until it succeeds or the sink is closed, so a down or firewalled
+// collector can never stall a caller's Write.
+func (this *NetworkSink) connectLoop() {
+	timeout := this.dialTimeout()
+	interval := this.RetryInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	for {
+		conn, err := net.DialTimeout(this.Network, this.Addr, timeout)
+
+		this.mtx.Lock()
+		if this.closed {
+			this.connecting = false
+			this.mtx.Unlock()
+			if conn != nil {
+				conn.Close()
+			}
+			return
+		}
+		if err == nil {
+			this.conn = conn
+			this.connecting = false
+			this.flushBacklogLocked()
+			this.mtx.Unlock()
+			return
+		}
+		this.mtx.Unlock()
+
+		time.Sleep(interval)
+	}
+}
+
+func (this *NetworkSink) enqueueLocked(buf []byte) {
+	limit := this.BacklogSize
+	if limit <= 0 {
+		limit = 1024
+	}
+	this.backlog = append(this.backlog, buf)
+	if over := len(this.backlog) - limit; over > 0 {
+		this.backlog = this.backlog[over:]
+	}
+}
+
+func (this *NetworkSink) flushBacklogLocked() {
+	remaining := this.backlog[:0]
+	for _, buf := range this.backlog {
+		if this.conn == nil {
+			remaining = append(remaining, buf)
+			continue
+		}
+		this.conn.SetWriteDeadline(time.Now().Add(this.dialTimeout()))
+		if _, err := this.conn.Write(buf); err != nil {
+			remaining = append(remaining, buf)
+			this.conn.Close()
+			this.conn = nil
+		}
+	}
+	this.backlog = remaining
+}
+
+// Close stops reconnect attempts and closes the active connection, if any.
+func (this *NetworkSink) Close() error {
+	this.mtx.Lock()
+	defer this.mtx.Unlock()
+	this.closed = true
+	if this.conn == nil {
+		return nil
+	}
+	err := this.conn.Close()
+	this.conn = nil
+	return err
+}
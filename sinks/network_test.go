@@ -0,0 +1,35 @@
+package sinks
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNetworkSinkWriteDoesNotBlockOnDial guards against Write dialing
+// synchronously: pointed at an address that will time out on connect, a
+// single Write must return well before DialTimeout elapses.
+func TestNetworkSinkWriteDoesNotBlockOnDial(t *testing.T) {
+	sink := &NetworkSink{
+		Network:     "tcp",
+		Addr:        "10.255.255.1:9", // non-routable: dial will hang until DialTimeout
+		DialTimeout: 2 * time.Second,
+	}
+	defer sink.Close()
+
+	start := time.Now()
+	if _, err := sink.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 200*time.Millisecond {
+		t.Fatalf("Write took %v, want it to return immediately (dial must happen in the background)", elapsed)
+	}
+}
+
+func TestNetworkSinkWriteAfterCloseErrors(t *testing.T) {
+	sink := &NetworkSink{Network: "tcp", Addr: "127.0.0.1:0"}
+	sink.Close()
+
+	if _, err := sink.Write([]byte("x")); err == nil {
+		t.Fatalf("Write after Close returned nil error, want net.ErrClosed")
+	}
+}
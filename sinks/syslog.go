@@ -0,0 +1,70 @@
+//go:build !windows
+
+package sinks
+
+import (
+	stdsyslog "log/syslog"
+	"sync"
+
+	log "github.com/iketutg/go-log"
+)
+
+// SyslogSink writes formatted records to syslog, mapping a log.Level to the
+// closest syslog severity. It implements log.LeveledWriter, so the mapping
+// is per-message and survives being wrapped by log.Logger.AddOutputAsync.
+type SyslogSink struct {
+	mtx sync.Mutex
+	w   *stdsyslog.Writer
+}
+
+// NewLocalSyslogSink connects to the local syslog daemon under the given
+// facility (e.g. syslog.LOG_USER, syslog.LOG_DAEMON) and tag.
+func NewLocalSyslogSink(facility stdsyslog.Priority, tag string) (*SyslogSink, error) {
+	w, err := stdsyslog.New(facility|stdsyslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{w: w}, nil
+}
+
+// NewRemoteSyslogSink dials a syslog server over the network ("udp" or
+// "tcp", RFC5424-capable daemons accept either) under the given facility
+// and tag.
+func NewRemoteSyslogSink(network, addr string, facility stdsyslog.Priority, tag string) (*SyslogSink, error) {
+	w, err := stdsyslog.Dial(network, addr, facility|stdsyslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{w: w}, nil
+}
+
+// Write implements io.Writer, logging at Info severity. Prefer WriteLevel
+// (used automatically by log.Logger) so severity reflects the record.
+func (this *SyslogSink) Write(p []byte) (int, error) {
+	return this.WriteLevel(log.Levels.Info, p)
+}
+
+// WriteLevel implements log.LeveledWriter.
+func (this *SyslogSink) WriteLevel(level log.Level, p []byte) (int, error) {
+	this.mtx.Lock()
+	defer this.mtx.Unlock()
+
+	msg := string(p)
+	switch {
+	case level >= log.Levels.Fatal:
+		return len(p), this.w.Crit(msg)
+	case level >= log.Levels.Error:
+		return len(p), this.w.Err(msg)
+	case level >= log.Levels.Warning:
+		return len(p), this.w.Warning(msg)
+	case level >= log.Levels.Info:
+		return len(p), this.w.Info(msg)
+	default:
+		return len(p), this.w.Debug(msg)
+	}
+}
+
+// Close closes the underlying syslog connection.
+func (this *SyslogSink) Close() error {
+	return this.w.Close()
+}